@@ -0,0 +1,141 @@
+package gclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenSource is the minimal surface AuthTransport needs from an OAuth
+// client in order to keep outbound requests authorized. goauth2.Client
+// satisfies this without gclient having to import goauth2.
+type TokenSource interface {
+	GetAccessToken() string
+	GetAccessTokenExpire() int64
+	GetRefreshToken() string
+	Refresh(ctx context.Context, refreshToken string) error
+}
+
+// refreshSkew is how long before the recorded expiry AuthTransport treats
+// the access token as stale, so a refresh happens before it actually
+// expires mid-request instead of after.
+const refreshSkew = 60 * time.Second
+
+// AuthTransport injects a "Bearer" Authorization header built from a
+// TokenSource, transparently refreshing the token when it is about to
+// expire or when the wrapped request comes back 401.
+type AuthTransport struct {
+	inner http.RoundTripper
+	token TokenSource
+	mu    sync.Mutex
+}
+
+// NewAuthTransport wraps inner (http.DefaultTransport if nil) with an
+// AuthTransport backed by token.
+func NewAuthTransport(token TokenSource, inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+
+	return &AuthTransport{
+		inner: inner,
+		token: token,
+	}
+}
+
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.expired() {
+		if err := t.refreshIfExpired(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	staleAccessToken := t.token.GetAccessToken()
+
+	resp, err := t.inner.RoundTrip(t.authorize(req))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	// The server says the token is bad right now, regardless of what our
+	// local expiry bookkeeping thinks, so this refreshes without consulting
+	// the preemptive expiry check (unless another request has already
+	// replaced the token, see forceRefresh).
+	if err := t.forceRefresh(req.Context(), staleAccessToken); err != nil {
+		return nil, err
+	}
+
+	retryReq, err := rewind(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.inner.RoundTrip(t.authorize(retryReq))
+}
+
+// rewind clones req with its body reset via GetBody, so a retried request
+// doesn't go out with the already-drained original body. Requests with no
+// body (GetBody is nil, e.g. GETs) are returned unchanged.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+
+	return clone, nil
+}
+
+func (t *AuthTransport) authorize(req *http.Request) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set(AuthorizationHeader, fmt.Sprintf(AuthorizationParam, t.token.GetAccessToken()))
+	return req
+}
+
+func (t *AuthTransport) expired() bool {
+	expire := t.token.GetAccessTokenExpire()
+	return expire == 0 || time.Now().Add(refreshSkew).Unix() >= expire
+}
+
+// refreshIfExpired refreshes the token if, under the lock, it's still
+// found to be expired. The double-check means concurrent requests racing
+// the same near-expiry window don't all redundantly call Refresh.
+func (t *AuthTransport) refreshIfExpired(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.expired() {
+		return nil
+	}
+
+	return t.token.Refresh(ctx, t.token.GetRefreshToken())
+}
+
+// forceRefresh calls Refresh without checking local expiry tracking, used on
+// the 401 path where the token is known bad regardless. staleAccessToken is
+// the token that produced the 401; if another in-flight request has
+// already refreshed past it by the time the lock is acquired, this is a
+// no-op instead of refreshing twice.
+func (t *AuthTransport) forceRefresh(ctx context.Context, staleAccessToken string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.GetAccessToken() != staleAccessToken {
+		return nil
+	}
+
+	return t.token.Refresh(ctx, t.token.GetRefreshToken())
+}
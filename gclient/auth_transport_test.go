@@ -0,0 +1,155 @@
+package gclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTokenSource is a minimal, concurrency-safe TokenSource for exercising
+// AuthTransport's refresh dance without a real OAuth round trip.
+type fakeTokenSource struct {
+	mu           sync.Mutex
+	accessToken  string
+	expire       int64
+	refreshToken string
+	refreshCalls int32
+}
+
+func (f *fakeTokenSource) GetAccessToken() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.accessToken
+}
+
+func (f *fakeTokenSource) GetAccessTokenExpire() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.expire
+}
+
+func (f *fakeTokenSource) GetRefreshToken() string {
+	return f.refreshToken
+}
+
+func (f *fakeTokenSource) Refresh(ctx context.Context, refreshToken string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	atomic.AddInt32(&f.refreshCalls, 1)
+	f.accessToken = fmt.Sprintf("refreshed-%d", f.refreshCalls)
+	f.expire = time.Now().Add(time.Hour).Unix()
+
+	return nil
+}
+
+func TestAuthTransportRefreshesBeforeExpiredRequest(t *testing.T) {
+	var gotAuth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get(AuthorizationHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	token := &fakeTokenSource{accessToken: "stale", expire: time.Now().Add(-time.Minute).Unix()}
+	transport := NewAuthTransport(token, http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if calls := atomic.LoadInt32(&token.refreshCalls); calls != 1 {
+		t.Errorf("refreshCalls = %d, want 1 (already-expired token should refresh before sending)", calls)
+	}
+	if len(gotAuth) != 1 || gotAuth[0] != fmt.Sprintf(AuthorizationParam, "refreshed-1") {
+		t.Errorf("server saw Authorization %v, want the refreshed token", gotAuth)
+	}
+}
+
+func TestAuthTransportForcesRefreshOn401(t *testing.T) {
+	var attempt int32
+	var gotAuth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get(AuthorizationHeader))
+		if atomic.AddInt32(&attempt, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// expire is far in the future, so the preemptive expired() check alone
+	// would never trigger a refresh; only the 401 path should force one.
+	token := &fakeTokenSource{accessToken: "still-locally-valid", expire: time.Now().Add(time.Hour).Unix()}
+	transport := NewAuthTransport(token, http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("RoundTrip() status = %d, want %d after the forced refresh and retry", resp.StatusCode, http.StatusOK)
+	}
+	if calls := atomic.LoadInt32(&token.refreshCalls); calls != 1 {
+		t.Errorf("refreshCalls = %d, want exactly 1 (401 must force a refresh despite local expiry bookkeeping)", calls)
+	}
+	if len(gotAuth) != 2 || gotAuth[0] == gotAuth[1] {
+		t.Errorf("server saw Authorization headers %v, want the retry to carry a different, refreshed token", gotAuth)
+	}
+}
+
+func TestAuthTransportResendsBodyOn401Retry(t *testing.T) {
+	var attempt int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(buf))
+		if atomic.AddInt32(&attempt, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	token := &fakeTokenSource{accessToken: "stale", expire: time.Now().Add(time.Hour).Unix()}
+	transport := NewAuthTransport(token, http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	want := []string{"payload", "payload"}
+	if len(bodies) != len(want) || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("server saw bodies %v, want %v (retry must resend the full body, not the drained original)", bodies, want)
+	}
+}
@@ -0,0 +1,168 @@
+package gclient
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Observer is notified before every retry RetryTransport performs, so
+// callers can wire metrics (Prometheus, OpenTelemetry, ...) without
+// RetryTransport depending on any particular backend.
+type Observer interface {
+	OnRetry(attempt int, req *http.Request, status int, delay time.Duration)
+}
+
+// RetryConfig tunes RetryTransport's backoff. The zero value is valid;
+// NewRetryTransport fills in the defaults noted below.
+type RetryConfig struct {
+	// Base is the backoff before the first retry. Defaults to 500ms.
+	Base time.Duration
+	// Cap bounds how large a single backoff can grow to. Defaults to 30s.
+	Cap time.Duration
+	// MaxAttempts is the maximum number of requests sent, including the
+	// first. Defaults to 5.
+	MaxAttempts int
+	// Observer, if set, is notified before every retry.
+	Observer Observer
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.Base <= 0 {
+		c.Base = 500 * time.Millisecond
+	}
+	if c.Cap <= 0 {
+		c.Cap = 30 * time.Second
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+
+	return c
+}
+
+// RetryTransport retries requests that come back 429 or 5xx using
+// full-jitter exponential backoff, honoring a Retry-After response header
+// when the server sends one. Only idempotent methods, and POSTs whose body
+// supports GetBody (e.g. was built by http.NewRequest from a *bytes.Buffer,
+// *bytes.Reader or *strings.Reader),
+// are retried; everything else is returned as-is.
+type RetryTransport struct {
+	inner  http.RoundTripper
+	config RetryConfig
+}
+
+// NewRetryTransport wraps inner (http.DefaultTransport if nil) with a
+// RetryTransport configured by config.
+func NewRetryTransport(inner http.RoundTripper, config RetryConfig) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+
+	return &RetryTransport{inner: inner, config: config.withDefaults()}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := retryableRequest(req)
+
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		sendReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return resp, err
+			}
+			sendReq = req.Clone(req.Context())
+			sendReq.Body = body
+		}
+
+		resp, err = t.inner.RoundTrip(sendReq)
+		if err != nil {
+			return resp, err
+		}
+
+		if !retryable || !shouldRetry(resp.StatusCode) || attempt >= t.config.MaxAttempts-1 {
+			return resp, err
+		}
+
+		delay := retryAfterDelay(resp.Header)
+		if delay <= 0 {
+			delay = fullJitterBackoff(t.config.Base, t.config.Cap, attempt)
+		}
+
+		if t.config.Observer != nil {
+			t.config.Observer.OnRetry(attempt+1, req, resp.StatusCode, delay)
+		}
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= 500 && status <= 599)
+}
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodTrace:   true,
+}
+
+func retryableRequest(req *http.Request) bool {
+	if idempotentMethods[req.Method] {
+		return true
+	}
+
+	return req.Method == http.MethodPost && req.Body != nil && req.GetBody != nil
+}
+
+// retryAfterDelay parses a Retry-After header in either of its documented
+// forms (delta-seconds or an HTTP-date), returning 0 if absent or
+// unparseable.
+func retryAfterDelay(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		return time.Until(at)
+	}
+
+	return 0
+}
+
+// fullJitterBackoff implements sleep = rand(0, min(cap, base*2^attempt)).
+func fullJitterBackoff(base, cap time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt; i++ {
+		if delay > cap/2 {
+			delay = cap
+			break
+		}
+		delay *= 2
+	}
+	if delay > cap {
+		delay = cap
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
@@ -0,0 +1,208 @@
+package gclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFullJitterBackoff(t *testing.T) {
+	const base = 500 * time.Millisecond
+	const cap = 30 * time.Second
+
+	for attempt := 0; attempt < 10; attempt++ {
+		for i := 0; i < 50; i++ {
+			delay := fullJitterBackoff(base, cap, attempt)
+			if delay < 0 {
+				t.Fatalf("fullJitterBackoff(attempt=%d) = %v, want >= 0", attempt, delay)
+			}
+			if delay > cap {
+				t.Fatalf("fullJitterBackoff(attempt=%d) = %v, want <= cap %v", attempt, delay, cap)
+			}
+		}
+	}
+}
+
+func TestFullJitterBackoffGrowsWithAttempt(t *testing.T) {
+	const base = 500 * time.Millisecond
+	const cap = 30 * time.Second
+
+	// The upper bound (base*2^attempt, capped) should grow monotonically,
+	// so the max observed delay over many samples should too.
+	var lastMax time.Duration
+	for attempt := 0; attempt < 6; attempt++ {
+		var max time.Duration
+		for i := 0; i < 200; i++ {
+			if delay := fullJitterBackoff(base, cap, attempt); delay > max {
+				max = delay
+			}
+		}
+		if max < lastMax {
+			t.Errorf("attempt %d: max observed delay %v fell below previous attempt's %v", attempt, max, lastMax)
+		}
+		lastMax = max
+	}
+}
+
+func TestRetryAfterDelaySeconds(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "5")
+
+	got := retryAfterDelay(h)
+	if got != 5*time.Second {
+		t.Errorf("retryAfterDelay() = %v, want 5s", got)
+	}
+}
+
+func TestRetryAfterDelayHTTPDate(t *testing.T) {
+	at := time.Now().Add(10 * time.Second)
+
+	h := http.Header{}
+	h.Set("Retry-After", at.UTC().Format(http.TimeFormat))
+
+	got := retryAfterDelay(h)
+	// Allow a little slack for the round trip through http.TimeFormat's
+	// second-level precision.
+	if got < 8*time.Second || got > 11*time.Second {
+		t.Errorf("retryAfterDelay() = %v, want ~10s", got)
+	}
+}
+
+func TestRetryAfterDelayAbsent(t *testing.T) {
+	if got := retryAfterDelay(http.Header{}); got != 0 {
+		t.Errorf("retryAfterDelay() = %v, want 0 for a missing header", got)
+	}
+}
+
+func TestRetryAfterDelayUnparseable(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "not-a-valid-value")
+
+	if got := retryAfterDelay(h); got != 0 {
+		t.Errorf("retryAfterDelay() = %v, want 0 for an unparseable header", got)
+	}
+}
+
+func TestRetryTransportRetriesBodylessGET(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, RetryConfig{Base: time.Millisecond, Cap: time.Millisecond})
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v, want a retried 200 response", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("server saw %d attempts, want 2 (one failure, one retry)", got)
+	}
+}
+
+func TestRetryTransportRetriesPostResendsBody(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(buf))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, RetryConfig{Base: time.Millisecond, Cap: time.Millisecond})
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	want := []string{"payload", "payload"}
+	if len(bodies) != len(want) || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("server saw bodies %v, want %v", bodies, want)
+	}
+}
+
+func TestRetryTransportStopsAtMaxAttempts(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, RetryConfig{Base: time.Millisecond, Cap: time.Millisecond, MaxAttempts: 3})
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	req = req.WithContext(context.Background())
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("RoundTrip() status = %d, want %d after exhausting retries", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("server saw %d attempts, want MaxAttempts=3", got)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{599, true},
+		{600, false},
+	}
+
+	for _, tt := range tests {
+		if got := shouldRetry(tt.status); got != tt.want {
+			t.Errorf("shouldRetry(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/morikuni/failure"
 	"github.com/sters/neko/gclient"
@@ -42,6 +43,7 @@ type Client struct {
 	accessTokenExpire int64
 	refreshToken      string
 	scope             string
+	tokenStore        TokenStore
 }
 
 func (c *Client) GetRefreshToken() string {
@@ -51,6 +53,12 @@ func (c *Client) GetAccessToken() string {
 	return c.accessToken
 }
 
+// GetAccessTokenExpire returns the unix timestamp at which accessToken
+// stops being valid.
+func (c *Client) GetAccessTokenExpire() int64 {
+	return c.accessTokenExpire
+}
+
 func NewClient(clientID string, clientSecret string) *Client {
 	return &Client{
 		clientID:     clientID,
@@ -76,6 +84,46 @@ func (c *Client) WithHTTPClient(client *http.Client) {
 	c.c = client
 }
 
+// WithTokenStore attaches a TokenStore that Authorization and Refresh will
+// persist to after every successful exchange.
+func (c *Client) WithTokenStore(store TokenStore) {
+	c.tokenStore = store
+}
+
+// LoadFromStore restores accessToken, accessTokenExpire and refreshToken
+// from the configured TokenStore, if one is set and it has anything saved.
+func (c *Client) LoadFromStore() error {
+	if c.tokenStore == nil {
+		return nil
+	}
+
+	token, err := c.tokenStore.Load()
+	if err != nil {
+		return failure.Wrap(err)
+	}
+	if token == nil {
+		return nil
+	}
+
+	c.accessToken = token.AccessToken
+	c.accessTokenExpire = token.AccessTokenExpire
+	c.refreshToken = token.RefreshToken
+
+	return nil
+}
+
+func (c *Client) persist() error {
+	if c.tokenStore == nil {
+		return nil
+	}
+
+	return c.tokenStore.Save(&StoredToken{
+		AccessToken:       c.accessToken,
+		AccessTokenExpire: c.accessTokenExpire,
+		RefreshToken:      c.refreshToken,
+	})
+}
+
 func (c *Client) GetOAuthURI() string {
 	builder := strings.Builder{}
 	builder.WriteString(oauthURI)
@@ -93,15 +141,27 @@ func (c *Client) GetOAuthURI() string {
 	return builder.String()
 }
 
+// Authorization exchanges an authorization code obtained via the
+// urn:ietf:wg:oauth:2.0:oob redirect for tokens. Prefer LoopbackFlow for
+// new integrations; Google is deprecating the oob redirect this still
+// assumes, but the exchange itself is shared with LoopbackFlow.
 func (c *Client) Authorization(ctx context.Context, authorizationCode string) error {
 	params := url.Values{}
-	params.Add("code", authorizationCode)
-	params.Add("client_id", c.clientID)
-	params.Add("client_secret", c.clientSecret)
-	params.Add("redirect_uri", redirectURI)
-	params.Add("grant_type", grantTypeAuthorizationCode)
-	params.Add("access_type", accessType)
+	params.Set("code", authorizationCode)
+	params.Set("client_id", c.clientID)
+	params.Set("client_secret", c.clientSecret)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("grant_type", grantTypeAuthorizationCode)
+	params.Set("access_type", accessType)
+
+	return c.exchangeToken(ctx, params)
+}
 
+// exchangeToken POSTs params to authorizationURI and stores the resulting
+// tokens, persisting them if a TokenStore is configured. It backs both
+// Authorization and LoopbackFlow, which differ only in how they obtain the
+// code and what they send alongside it (redirect_uri, code_verifier).
+func (c *Client) exchangeToken(ctx context.Context, params url.Values) error {
 	req, err := http.NewRequest(
 		http.MethodPost,
 		authorizationURI,
@@ -133,10 +193,10 @@ func (c *Client) Authorization(ctx context.Context, authorizationCode string) er
 	}
 
 	c.accessToken = response.AccessToken
-	c.accessTokenExpire = response.ExpiresIn
+	c.accessTokenExpire = time.Now().Unix() + response.ExpiresIn
 	c.refreshToken = response.RefreshToken
 
-	return nil
+	return c.persist()
 }
 
 func (c *Client) Refresh(ctx context.Context, refreshToken string) error {
@@ -180,11 +240,11 @@ func (c *Client) Refresh(ctx context.Context, refreshToken string) error {
 
 	if response.AccessToken != "" {
 		c.accessToken = response.AccessToken
-		c.accessTokenExpire = response.ExpiresIn
+		c.accessTokenExpire = time.Now().Unix() + response.ExpiresIn
 	}
 	if response.RefreshToken != "" {
 		c.refreshToken = response.RefreshToken
 	}
 
-	return nil
+	return c.persist()
 }
@@ -0,0 +1,151 @@
+package goauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/morikuni/failure"
+	"github.com/pkg/browser"
+)
+
+const (
+	loopbackCallbackPath    = "/callback"
+	codeChallengeMethodS256 = "S256"
+
+	// loopbackTimeout bounds how long Run waits for the browser redirect,
+	// so a flow nobody completes doesn't hang the caller forever.
+	loopbackTimeout = 5 * time.Minute
+)
+
+// LoopbackFlow runs the OAuth "Installed App" flow over a local loopback
+// listener with PKCE, replacing the urn:ietf:wg:oauth:2.0:oob redirect
+// Google is deprecating.
+type LoopbackFlow struct {
+	c *Client
+
+	// OpenBrowser launches the user's default browser with the auth URL
+	// when true. When false, Run neither opens nor returns the auth URL: it
+	// is built inside Run from that call's code verifier and state, and Run
+	// only waits for the callback.
+	OpenBrowser bool
+}
+
+// NewLoopbackFlow returns a LoopbackFlow that authorizes c.
+func NewLoopbackFlow(c *Client, openBrowser bool) *LoopbackFlow {
+	return &LoopbackFlow{c: c, OpenBrowser: openBrowser}
+}
+
+// Run binds a loopback listener, drives the user through the auth URL, and
+// exchanges the resulting code for tokens on the underlying Client. It
+// blocks until the browser redirects back, ctx is cancelled, or
+// loopbackTimeout elapses, whichever comes first, and always closes its
+// listener before returning.
+func (f *LoopbackFlow) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return failure.Wrap(err)
+	}
+	defer listener.Close()
+
+	verifier, err := generateCodeVerifier()
+	if err != nil {
+		return failure.Wrap(err)
+	}
+
+	state, err := generateState()
+	if err != nil {
+		return failure.Wrap(err)
+	}
+
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), loopbackCallbackPath)
+
+	if f.OpenBrowser {
+		if err := browser.OpenURL(f.authURL(redirectURI, codeChallengeS256(verifier), state)); err != nil {
+			return failure.Wrap(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, loopbackTimeout)
+	defer cancel()
+
+	code, err := serveLoopbackCallback(ctx, listener, state)
+	if err != nil {
+		return failure.Wrap(err)
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	params.Set("client_id", f.c.clientID)
+	params.Set("client_secret", f.c.clientSecret)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("grant_type", grantTypeAuthorizationCode)
+	params.Set("access_type", accessType)
+	params.Set("code_verifier", verifier)
+
+	return f.c.exchangeToken(ctx, params)
+}
+
+func (f *LoopbackFlow) authURL(redirectURI, codeChallenge, state string) string {
+	params := url.Values{}
+	params.Set("client_id", f.c.clientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("scope", f.c.scope)
+	params.Set("access_type", accessType)
+	params.Set("response_type", responseType)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", codeChallengeMethodS256)
+	params.Set("state", state)
+
+	return oauthURI + "?" + params.Encode()
+}
+
+// serveLoopbackCallback serves exactly one request on listener, validating
+// state and extracting the "code" query parameter, then shuts the server
+// down. listener is always closed, on every return path, by the server
+// shutdown this triggers.
+func serveLoopbackCallback(ctx context.Context, listener net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(loopbackCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		switch {
+		case query.Get("error") != "":
+			fmt.Fprintf(w, "Authorization failed (%s). You can close this tab.", query.Get("error"))
+			resultCh <- result{err: fmt.Errorf("goauth2: authorization denied: %s", query.Get("error"))}
+		case query.Get("state") != state:
+			http.Error(w, "Authorization failed: state mismatch. You can close this tab.", http.StatusBadRequest)
+			resultCh <- result{err: errors.New("goauth2: state mismatch in loopback callback")}
+		case query.Get("code") == "":
+			http.Error(w, "Authorization failed: no code. You can close this tab.", http.StatusBadRequest)
+			resultCh <- result{err: errors.New("goauth2: loopback callback had no code")}
+		default:
+			fmt.Fprint(w, "Authorized. You can close this tab.")
+			resultCh <- result{code: query.Get("code")}
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	defer server.Shutdown(context.Background())
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", failure.Wrap(ctx.Err())
+	}
+}
@@ -0,0 +1,144 @@
+package goauth2
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type loopbackResult struct {
+	code string
+	err  error
+}
+
+func runLoopbackCallback(t *testing.T, state string) (net.Listener, <-chan loopbackResult) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+
+	resultCh := make(chan loopbackResult, 1)
+	go func() {
+		code, err := serveLoopbackCallback(context.Background(), listener, state)
+		resultCh <- loopbackResult{code: code, err: err}
+	}()
+
+	return listener, resultCh
+}
+
+func TestServeLoopbackCallbackSuccess(t *testing.T) {
+	listener, resultCh := runLoopbackCallback(t, "expected-state")
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/callback?code=abc123&state=expected-state", listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("callback status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	res := waitForResult(t, resultCh)
+	if res.err != nil {
+		t.Fatalf("serveLoopbackCallback() error = %v", res.err)
+	}
+	if res.code != "abc123" {
+		t.Errorf("serveLoopbackCallback() code = %q, want %q", res.code, "abc123")
+	}
+}
+
+func TestServeLoopbackCallbackStateMismatch(t *testing.T) {
+	listener, resultCh := runLoopbackCallback(t, "expected-state")
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/callback?code=abc123&state=wrong-state", listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("callback status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	res := waitForResult(t, resultCh)
+	if res.err == nil {
+		t.Error("serveLoopbackCallback() error = nil, want a state mismatch error")
+	}
+}
+
+func TestServeLoopbackCallbackNoCode(t *testing.T) {
+	listener, resultCh := runLoopbackCallback(t, "expected-state")
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/callback?state=expected-state", listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("callback status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	res := waitForResult(t, resultCh)
+	if res.err == nil {
+		t.Error("serveLoopbackCallback() error = nil, want a missing-code error")
+	}
+}
+
+func TestServeLoopbackCallbackAuthorizationDenied(t *testing.T) {
+	listener, resultCh := runLoopbackCallback(t, "expected-state")
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/callback?error=access_denied&state=expected-state", listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("callback status = %d, want %d (the denial is reported via the returned error, not the HTTP status)", resp.StatusCode, http.StatusOK)
+	}
+
+	res := waitForResult(t, resultCh)
+	if res.err == nil {
+		t.Error("serveLoopbackCallback() error = nil, want an authorization-denied error")
+	}
+}
+
+func TestServeLoopbackCallbackCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resultCh := make(chan loopbackResult, 1)
+	go func() {
+		code, err := serveLoopbackCallback(ctx, listener, "expected-state")
+		resultCh <- loopbackResult{code: code, err: err}
+	}()
+
+	cancel()
+
+	res := waitForResult(t, resultCh)
+	if res.err == nil {
+		t.Error("serveLoopbackCallback() error = nil, want ctx.Err() once cancelled")
+	}
+}
+
+func waitForResult(t *testing.T, resultCh <-chan loopbackResult) loopbackResult {
+	t.Helper()
+
+	select {
+	case res := <-resultCh:
+		return res
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveLoopbackCallback did not return in time")
+		return loopbackResult{}
+	}
+}
@@ -0,0 +1,70 @@
+package goauth2
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCodeVerifier(t *testing.T) {
+	verifier, err := generateCodeVerifier()
+	if err != nil {
+		t.Fatalf("generateCodeVerifier() error = %v", err)
+	}
+
+	if n := len(verifier); n < 43 || n > 128 {
+		t.Errorf("len(verifier) = %d, want between 43 and 128 per RFC 7636", n)
+	}
+
+	if _, err := base64.RawURLEncoding.DecodeString(verifier); err != nil {
+		t.Errorf("verifier %q is not valid base64url: %v", verifier, err)
+	}
+
+	other, err := generateCodeVerifier()
+	if err != nil {
+		t.Fatalf("generateCodeVerifier() error = %v", err)
+	}
+	if verifier == other {
+		t.Error("two calls to generateCodeVerifier() returned the same value")
+	}
+}
+
+func TestGenerateState(t *testing.T) {
+	state, err := generateState()
+	if err != nil {
+		t.Fatalf("generateState() error = %v", err)
+	}
+
+	if state == "" {
+		t.Error("generateState() returned an empty string")
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(state); err != nil {
+		t.Errorf("state %q is not valid base64url: %v", state, err)
+	}
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// Known-answer test vector from RFC 7636 appendix B.
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	const want = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if got := codeChallengeS256(verifier); got != want {
+		t.Errorf("codeChallengeS256(%q) = %q, want %q", verifier, got, want)
+	}
+}
+
+func TestCodeChallengeS256IsSha256(t *testing.T) {
+	const verifier = "some-other-verifier-value"
+	sum := sha256.Sum256([]byte(verifier))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	got := codeChallengeS256(verifier)
+	if got != want {
+		t.Errorf("codeChallengeS256(%q) = %q, want %q", verifier, got, want)
+	}
+
+	if strings.ContainsAny(got, "+/=") {
+		t.Errorf("codeChallengeS256(%q) = %q, want unpadded base64url (no +, /, =)", verifier, got)
+	}
+}
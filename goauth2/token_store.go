@@ -0,0 +1,65 @@
+package goauth2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/morikuni/failure"
+)
+
+// StoredToken is the subset of Client state that needs to survive a
+// process restart.
+type StoredToken struct {
+	AccessToken       string `json:"access_token"`
+	AccessTokenExpire int64  `json:"access_token_expire"`
+	RefreshToken      string `json:"refresh_token"`
+}
+
+// TokenStore persists and restores a StoredToken so callers don't have to
+// shuttle a refresh token through the environment on every run.
+type TokenStore interface {
+	Load() (*StoredToken, error)
+	Save(token *StoredToken) error
+}
+
+// FileTokenStore is a TokenStore backed by a single JSON file on disk.
+type FileTokenStore struct {
+	path string
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load reads the token file. A missing file is not an error; it returns a
+// nil token so callers can fall through to the interactive auth flow.
+func (s *FileTokenStore) Load() (*StoredToken, error) {
+	buf, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, failure.Wrap(err)
+	}
+
+	var token StoredToken
+	if err := json.Unmarshal(buf, &token); err != nil {
+		return nil, failure.Wrap(err)
+	}
+
+	return &token, nil
+}
+
+func (s *FileTokenStore) Save(token *StoredToken) error {
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return failure.Wrap(err)
+	}
+
+	if err := ioutil.WriteFile(s.path, buf, 0o600); err != nil {
+		return failure.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package gphoto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	albumsEndpoint = "albums"
+)
+
+type (
+	Album struct {
+		ID                    string `json:"id,omitempty"`
+		Title                 string `json:"title,omitempty"`
+		ProductURL            string `json:"productUrl,omitempty"`
+		IsWriteable           bool   `json:"isWriteable,omitempty"`
+		MediaItemsCount       string `json:"mediaItemsCount,omitempty"`
+		CoverPhotoBaseURL     string `json:"coverPhotoBaseUrl,omitempty"`
+		CoverPhotoMediaItemID string `json:"coverPhotoMediaItemId,omitempty"`
+	}
+
+	AlbumsListRequest struct {
+		PagerRequest
+		ExcludeNonAppCreatedData bool `json:"excludeNonAppCreatedData,omitempty"`
+	}
+	AlbumsListResponse struct {
+		PagerResponse
+		Albums []*Album `json:"albums,omitempty"`
+	}
+
+	AlbumsCreateRequest struct {
+		Album *Album `json:"album"`
+	}
+
+	AlbumsBatchAddMediaItemsRequest struct {
+		MediaItemIDs []string `json:"mediaItemIds"`
+	}
+	AlbumsBatchRemoveMediaItemsRequest struct {
+		MediaItemIDs []string `json:"mediaItemIds"`
+	}
+
+	// AlbumsService groups every /v1/albums endpoint under Client.Albums.
+	AlbumsService struct {
+		c *Client
+	}
+)
+
+// List returns one page of the user's albums, see
+// https://developers.google.com/photos/library/reference/rest/v1/albums/list
+func (s *AlbumsService) List(ctx context.Context, req *AlbumsListRequest) (*AlbumsListResponse, error) {
+	endpoint := fmt.Sprintf(
+		"%s?%s",
+		albumsEndpoint,
+		url.Values{
+			"pageSize":                 {req.PageSize},
+			"pageToken":                {req.PageToken},
+			"excludeNonAppCreatedData": {fmt.Sprintf("%t", req.ExcludeNonAppCreatedData)},
+		}.Encode(),
+	)
+
+	return do[*AlbumsListRequest, *AlbumsListResponse](ctx, s.c, http.MethodGet, endpoint, req)
+}
+
+// Get looks up a single album by ID.
+func (s *AlbumsService) Get(ctx context.Context, albumID string) (*Album, error) {
+	return do[any, *Album](ctx, s.c, http.MethodGet, albumsEndpoint+"/"+albumID, nil)
+}
+
+// Create makes a new, empty album titled title.
+func (s *AlbumsService) Create(ctx context.Context, title string) (*Album, error) {
+	return do[*AlbumsCreateRequest, *Album](
+		ctx,
+		s.c,
+		http.MethodPost,
+		albumsEndpoint,
+		&AlbumsCreateRequest{Album: &Album{Title: title}},
+	)
+}
+
+// AddMediaItems adds the given media items to albumID.
+func (s *AlbumsService) AddMediaItems(ctx context.Context, albumID string, mediaItemIDs []string) error {
+	_, err := do[*AlbumsBatchAddMediaItemsRequest, struct{}](
+		ctx,
+		s.c,
+		http.MethodPost,
+		albumsEndpoint+"/"+albumID+":batchAddMediaItems",
+		&AlbumsBatchAddMediaItemsRequest{MediaItemIDs: mediaItemIDs},
+	)
+
+	return err
+}
+
+// RemoveMediaItems removes the given media items from albumID.
+func (s *AlbumsService) RemoveMediaItems(ctx context.Context, albumID string, mediaItemIDs []string) error {
+	_, err := do[*AlbumsBatchRemoveMediaItemsRequest, struct{}](
+		ctx,
+		s.c,
+		http.MethodPost,
+		albumsEndpoint+"/"+albumID+":batchRemoveMediaItems",
+		&AlbumsBatchRemoveMediaItemsRequest{MediaItemIDs: mediaItemIDs},
+	)
+
+	return err
+}
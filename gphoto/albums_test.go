@@ -0,0 +1,147 @@
+package gphoto
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAlbumsServiceList(t *testing.T) {
+	var gotMethod, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path + "?" + r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AlbumsListResponse{
+			Albums: []*Album{{ID: "a1", Title: "Trip"}},
+		})
+	}))
+	defer srv.Close()
+
+	s := &AlbumsService{c: newTestClient(srv)}
+
+	resp, err := s.List(context.Background(), &AlbumsListRequest{
+		PagerRequest: PagerRequest{PageSize: "10"},
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if gotMethod != http.MethodGet {
+		t.Errorf("List() issued %s, want GET", gotMethod)
+	}
+	if got, want := gotPath, "/v1/albums?excludeNonAppCreatedData=false&pageSize=10&pageToken="; got != want {
+		t.Errorf("List() requested %q, want %q", got, want)
+	}
+	if len(resp.Albums) != 1 || resp.Albums[0].ID != "a1" {
+		t.Errorf("List() albums = %+v, want one album a1", resp.Albums)
+	}
+}
+
+func TestAlbumsServiceGet(t *testing.T) {
+	var gotMethod, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Album{ID: "a1", Title: "Trip"})
+	}))
+	defer srv.Close()
+
+	s := &AlbumsService{c: newTestClient(srv)}
+
+	album, err := s.Get(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if gotMethod != http.MethodGet {
+		t.Errorf("Get() issued %s, want GET", gotMethod)
+	}
+	if want := "/v1/albums/a1"; gotPath != want {
+		t.Errorf("Get() requested %q, want %q", gotPath, want)
+	}
+	if album.ID != "a1" {
+		t.Errorf("Get() album = %+v, want ID a1", album)
+	}
+}
+
+func TestAlbumsServiceCreate(t *testing.T) {
+	var gotBody AlbumsCreateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		if err := json.Unmarshal(buf, &gotBody); err != nil {
+			t.Fatalf("unmarshaling request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Album{ID: "new", Title: gotBody.Album.Title})
+	}))
+	defer srv.Close()
+
+	s := &AlbumsService{c: newTestClient(srv)}
+
+	album, err := s.Create(context.Background(), "Vacation")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if gotBody.Album == nil || gotBody.Album.Title != "Vacation" {
+		t.Errorf("server saw album %+v, want Title=Vacation", gotBody.Album)
+	}
+	if album.ID != "new" {
+		t.Errorf("Create() album = %+v, want ID new", album)
+	}
+}
+
+func TestAlbumsServiceAddAndRemoveMediaItems(t *testing.T) {
+	var gotPaths []string
+	var gotBodies []AlbumsBatchAddMediaItemsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		var body AlbumsBatchAddMediaItemsRequest
+		if err := json.Unmarshal(buf, &body); err != nil {
+			t.Fatalf("unmarshaling request body: %v", err)
+		}
+		gotBodies = append(gotBodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct{}{})
+	}))
+	defer srv.Close()
+
+	s := &AlbumsService{c: newTestClient(srv)}
+
+	if err := s.AddMediaItems(context.Background(), "a1", []string{"m1", "m2"}); err != nil {
+		t.Fatalf("AddMediaItems() error = %v", err)
+	}
+	if err := s.RemoveMediaItems(context.Background(), "a1", []string{"m1"}); err != nil {
+		t.Fatalf("RemoveMediaItems() error = %v", err)
+	}
+
+	wantPaths := []string{"/v1/albums/a1:batchAddMediaItems", "/v1/albums/a1:batchRemoveMediaItems"}
+	if len(gotPaths) != len(wantPaths) {
+		t.Fatalf("got paths %v, want %v", gotPaths, wantPaths)
+	}
+	for i, want := range wantPaths {
+		if gotPaths[i] != want {
+			t.Errorf("request %d path = %q, want %q", i, gotPaths[i], want)
+		}
+	}
+	if len(gotBodies) != 2 || len(gotBodies[0].MediaItemIDs) != 2 || gotBodies[0].MediaItemIDs[0] != "m1" {
+		t.Errorf("got bodies %+v, want AddMediaItems to send [m1 m2]", gotBodies)
+	}
+}
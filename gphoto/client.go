@@ -4,12 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/morikuni/failure"
 	"github.com/sters/neko/gclient"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -21,8 +22,11 @@ const (
 
 type (
 	Client struct {
-		c     *http.Client
-		token string
+		c       *http.Client
+		limiter *rate.Limiter
+
+		Albums     *AlbumsService
+		MediaItems *MediaItemsService
 	}
 
 	PagerRequest struct {
@@ -93,6 +97,11 @@ type (
 		MediaMetadata   *MediaMetadata   `json:"mediaMetadata,omitempty"`
 		ContributorInfo *ContributorInfo `json:"contributorInfo,omitempty"`
 		Filename        string           `json:"filename,omitempty"`
+
+		// client is attached by whichever Client call returned this item, so
+		// Download can issue an authorized request without callers having to
+		// pass the Client back in.
+		client *Client
 	}
 	MediaMetadata struct {
 		CreationTime string `json:"creationTime,omitempty"`
@@ -165,62 +174,114 @@ const (
 	VideoProcessingStatusFailed      VideoProcessingStatus = "FAILED"
 )
 
-func NewClient(c *http.Client, token string) *Client {
-	return &Client{
-		c:     c,
-		token: token,
+// ClientOption configures NewClient.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	retryConfig gclient.RetryConfig
+}
+
+// WithRetryConfig overrides the gclient.RetryConfig NewClient wraps every
+// request with; the zero value (NewClient's default) already retries
+// 429/5xx with exponential backoff, so this is only needed to tune the
+// backoff or attach an Observer.
+func WithRetryConfig(config gclient.RetryConfig) ClientOption {
+	return func(o *clientOptions) {
+		o.retryConfig = config
 	}
 }
 
-func (c *Client) request(ctx context.Context, endpoint string, req interface{}, response interface{}) error {
-	buf, err := json.Marshal(req)
-	if err != nil {
-		return failure.Wrap(err)
+// NewClient builds a Client whose requests are kept authorized for the
+// lifetime of c by wrapping its transport with a gclient.AuthTransport, in
+// turn wrapping a gclient.RetryTransport so 429/5xx responses from the
+// Library API are retried instead of surfacing as empty or failed results.
+// Pass a *goauth2.Client (or anything else satisfying gclient.TokenSource)
+// as oauth; long-running callers don't need to know when or how tokens get
+// refreshed.
+func NewClient(c *http.Client, oauth gclient.TokenSource, opts ...ClientOption) *Client {
+	if c == nil {
+		c = &http.Client{}
 	}
 
-	rawRequest, err := http.NewRequest(
-		http.MethodPost,
-		baseURL+endpoint,
-		bytes.NewBuffer(buf),
-	)
-	if err != nil {
-		return failure.Wrap(err)
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
 
+	transport := gclient.NewRetryTransport(c.Transport, options.retryConfig)
+	c.Transport = gclient.NewAuthTransport(oauth, transport)
+
+	client := &Client{c: c}
+	client.Albums = &AlbumsService{c: client}
+	client.MediaItems = &MediaItemsService{c: client}
+
+	return client
+}
+
+// WithRateLimiter bounds how often c issues requests, so a bulk consumer
+// doesn't blow through the Library API's per-project daily quota.
+func (c *Client) WithRateLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// do marshals req as the body of a method request to endpoint (GET
+// requests are sent without a body, so req is not sent for them),
+// unmarshals the response into TResp, and is the shared plumbing every
+// Library API call (search, albums, uploads) is built on.
+func do[TReq, TResp any](ctx context.Context, c *Client, method string, endpoint string, req TReq) (TResp, error) {
+	var response TResp
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return response, failure.Wrap(err)
+		}
+	}
+
+	var body io.Reader
+	if method != http.MethodGet {
+		buf, err := json.Marshal(req)
+		if err != nil {
+			return response, failure.Wrap(err)
+		}
+		body = bytes.NewBuffer(buf)
+	}
+
+	rawRequest, err := http.NewRequest(method, baseURL+endpoint, body)
+	if err != nil {
+		return response, failure.Wrap(err)
+	}
 	rawRequest = rawRequest.WithContext(ctx)
 
 	rawRequest.Header = http.Header{}
-	rawRequest.Header.Set(
-		gclient.AuthorizationHeader,
-		fmt.Sprintf(gclient.AuthorizationParam, c.token),
-	)
 	rawRequest.Header.Set(gclient.ContentTypeHeader, gclient.ContentTypeJSON)
 
 	rawResponse, err := c.c.Do(rawRequest)
 	if err != nil {
-		return failure.Wrap(err)
+		return response, failure.Wrap(err)
 	}
+	defer rawResponse.Body.Close()
 
 	responseBuf, err := ioutil.ReadAll(rawResponse.Body)
 	if err != nil {
-		return failure.Wrap(err)
+		return response, failure.Wrap(err)
 	}
 
-	err = json.Unmarshal(responseBuf, response)
-	if err != nil {
-		return failure.Wrap(err)
+	if rawResponse.StatusCode >= http.StatusBadRequest {
+		return response, failure.Wrap(parseAPIError(rawResponse.StatusCode, responseBuf))
+	}
+
+	if err := json.Unmarshal(responseBuf, &response); err != nil {
+		return response, failure.Wrap(err)
 	}
 
-	return nil
+	return response, nil
 }
 
 func (c *Client) MediaItemsSearch(ctx context.Context, req *MediaItemsSearchRequest) (*MediaItemsSearchResponse, error) {
-	var response MediaItemsSearchResponse
-	err := c.request(
-		ctx,
-		mediaItemsSearchEndpoint,
-		req,
-		&response,
-	)
+	response, err := do[*MediaItemsSearchRequest, MediaItemsSearchResponse](ctx, c, http.MethodPost, mediaItemsSearchEndpoint, req)
+	for _, item := range response.MediaItems {
+		item.client = c
+	}
+
 	return &response, err
 }
@@ -0,0 +1,41 @@
+package gphoto
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// APIError is the typed form of the standard Google API error envelope,
+// see https://cloud.google.com/apis/design/errors#http_mapping
+// Calls built on do return one whenever the server responds with a 4xx or
+// 5xx status, so callers can tell e.g. PERMISSION_DENIED apart from
+// RESOURCE_EXHAUSTED.
+type APIError struct {
+	HTTPStatusCode int              `json:"-"`
+	Code           int              `json:"code,omitempty"`
+	Message        string           `json:"message,omitempty"`
+	Status         string           `json:"status,omitempty"`
+	Details        []map[string]any `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gphoto: api error: http %d %s: %s", e.HTTPStatusCode, e.Status, e.Message)
+}
+
+type apiErrorEnvelope struct {
+	Error *APIError `json:"error"`
+}
+
+// parseAPIError parses buf as the standard Google error envelope. If buf
+// doesn't parse as one, a generic APIError carrying the raw body is
+// returned instead, so the status code is never silently dropped.
+func parseAPIError(statusCode int, buf []byte) *APIError {
+	var envelope apiErrorEnvelope
+	if err := json.Unmarshal(buf, &envelope); err != nil || envelope.Error == nil {
+		return &APIError{HTTPStatusCode: statusCode, Message: string(buf)}
+	}
+
+	envelope.Error.HTTPStatusCode = statusCode
+
+	return envelope.Error
+}
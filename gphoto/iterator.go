@@ -0,0 +1,102 @@
+package gphoto
+
+import (
+	"context"
+	"io"
+
+	"github.com/morikuni/failure"
+)
+
+// MediaItemResult is one item yielded by a stream. Err is set, and
+// MediaItem is nil, when the underlying search failed.
+type MediaItemResult struct {
+	MediaItem *MediaItem
+	Err       error
+
+	// PartitionIndex is the index into WalkMediaItemsOptions.Requests this
+	// result came from (zero otherwise, e.g. for MediaItemsSearchIterator.
+	// Stream). WalkMediaItems interleaves partitions as they complete
+	// rather than emitting them in order, so callers that need requests
+	// back in a specific order can group by this field themselves.
+	PartitionIndex int
+}
+
+// MediaItemsSearchIterator walks every page of a MediaItemsSearch query,
+// hiding NextPageToken bookkeeping from the caller.
+type MediaItemsSearchIterator struct {
+	c   *Client
+	ctx context.Context
+	req *MediaItemsSearchRequest
+
+	items []*MediaItem
+	pos   int
+	done  bool
+}
+
+// MediaItemsSearchIterator returns an iterator over every MediaItem
+// matching req, transparently following NextPageToken until the API
+// reports no more pages. ctx bounds every underlying page fetch.
+func (c *Client) MediaItemsSearchIterator(ctx context.Context, req *MediaItemsSearchRequest) *MediaItemsSearchIterator {
+	reqCopy := *req
+
+	return &MediaItemsSearchIterator{
+		c:   c,
+		ctx: ctx,
+		req: &reqCopy,
+	}
+}
+
+// Next returns the next MediaItem, fetching additional pages as needed. It
+// returns io.EOF once every page has been consumed.
+func (it *MediaItemsSearchIterator) Next() (*MediaItem, error) {
+	for it.pos >= len(it.items) {
+		if it.done {
+			return nil, io.EOF
+		}
+
+		resp, err := it.c.MediaItemsSearch(it.ctx, it.req)
+		if err != nil {
+			return nil, failure.Wrap(err)
+		}
+
+		it.items = resp.MediaItems
+		it.pos = 0
+		it.req.PageToken = resp.NextPageToken
+		it.done = resp.NextPageToken == ""
+	}
+
+	item := it.items[it.pos]
+	it.pos++
+
+	return item, nil
+}
+
+// Stream drains the iterator into a channel, stopping early if ctx is
+// cancelled. The channel is closed once every page has been consumed or an
+// error is sent.
+func (it *MediaItemsSearchIterator) Stream(ctx context.Context) <-chan MediaItemResult {
+	out := make(chan MediaItemResult)
+
+	go func() {
+		defer close(out)
+
+		for {
+			item, err := it.Next()
+			if err == io.EOF {
+				return
+			}
+
+			result := MediaItemResult{MediaItem: item, Err: err}
+			select {
+			case out <- result:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
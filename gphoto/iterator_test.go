@@ -0,0 +1,108 @@
+package gphoto
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSearchServer answers mediaItems:search requests by looking up the
+// request's PageToken in pages, failing the test if it sees one it wasn't
+// told about.
+func newSearchServer(t *testing.T, pages map[string]MediaItemsSearchResponse) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MediaItemsSearchRequest
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		if err := json.Unmarshal(buf, &req); err != nil {
+			t.Fatalf("unmarshaling request body: %v", err)
+		}
+
+		resp, ok := pages[req.PageToken]
+		if !ok {
+			t.Fatalf("unexpected pageToken %q", req.PageToken)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encoding response: %v", err)
+		}
+	}))
+}
+
+func TestMediaItemsSearchIteratorPaginates(t *testing.T) {
+	pages := map[string]MediaItemsSearchResponse{
+		"": {
+			PagerResponse: PagerResponse{NextPageToken: "page2"},
+			MediaItems:    []*MediaItem{{ID: "1"}, {ID: "2"}},
+		},
+		"page2": {
+			MediaItems: []*MediaItem{{ID: "3"}},
+		},
+	}
+	srv := newSearchServer(t, pages)
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	it := c.MediaItemsSearchIterator(context.Background(), &MediaItemsSearchRequest{})
+
+	var ids []string
+	for {
+		item, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	want := []string{"1", "2", "3"}
+	if len(ids) != len(want) {
+		t.Fatalf("got ids %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("ids[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+}
+
+func TestMediaItemsSearchIteratorStreamStopsOnCancel(t *testing.T) {
+	pages := map[string]MediaItemsSearchResponse{
+		"": {
+			PagerResponse: PagerResponse{NextPageToken: "page2"},
+			MediaItems:    []*MediaItem{{ID: "1"}},
+		},
+		"page2": {
+			MediaItems: []*MediaItem{{ID: "2"}},
+		},
+	}
+	srv := newSearchServer(t, pages)
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	it := c.MediaItemsSearchIterator(ctx, &MediaItemsSearchRequest{})
+
+	out := it.Stream(ctx)
+
+	first := <-out
+	if first.Err != nil || first.MediaItem == nil || first.MediaItem.ID != "1" {
+		t.Fatalf("first result = %+v, want item 1", first)
+	}
+
+	cancel()
+
+	// Stream must close out once ctx is cancelled, even with pages left.
+	for range out {
+	}
+}
@@ -0,0 +1,126 @@
+package gphoto
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestUploadServer simulates the start/upload/upload-finalize resumable
+// dance, recording the offset and body of every chunk command it receives.
+func newTestUploadServer(t *testing.T, chunks *[]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get(uploadCommandHeader) {
+		case uploadCommandStart:
+			w.Header().Set(uploadURLHeader, "http://"+r.Host+"/upload")
+			w.WriteHeader(http.StatusOK)
+		case uploadCommandUpload, uploadCommandUploadFinalize:
+			buf, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("reading chunk body: %v", err)
+			}
+			*chunks = append(*chunks, r.Header.Get(uploadOffsetHeader)+":"+string(buf))
+			w.WriteHeader(http.StatusOK)
+			if r.Header.Get(uploadCommandHeader) == uploadCommandUploadFinalize {
+				_, _ = w.Write([]byte("upload-token"))
+			}
+		default:
+			t.Fatalf("unexpected %s: %q", uploadCommandHeader, r.Header.Get(uploadCommandHeader))
+		}
+	}))
+}
+
+// redirectToTransport sends every request to target instead of whatever
+// host it was built for, so uploadResumable's hardcoded baseURL can be
+// pointed at an httptest.Server.
+type redirectToTransport struct {
+	targetHost string
+}
+
+func (t redirectToTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.targetHost
+	req.Host = t.targetHost
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(srv *httptest.Server) *Client {
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Client{c: &http.Client{Transport: redirectToTransport{targetHost: u.Host}}}
+}
+
+func TestUploadResumableChunksOffsets(t *testing.T) {
+	var chunks []string
+	srv := newTestUploadServer(t, &chunks)
+	defer srv.Close()
+
+	content := "abcdefghij" // 10 bytes
+	file := UploadFile{
+		Name:        "test.bin",
+		ContentType: "application/octet-stream",
+		Size:        int64(len(content)),
+		Reader:      strings.NewReader(content),
+	}
+
+	s := &MediaItemsService{
+		c:                  newTestClient(srv),
+		ResumableChunkSize: 4,
+	}
+
+	token, err := s.uploadResumable(context.Background(), file)
+	if err != nil {
+		t.Fatalf("uploadResumable() error = %v", err)
+	}
+	if token != "upload-token" {
+		t.Errorf("uploadResumable() = %q, want %q", token, "upload-token")
+	}
+
+	want := []string{"0:abcd", "4:efgh", "8:ij"}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks %v, want %d chunks %v", len(chunks), chunks, len(want), want)
+	}
+	for i, w := range want {
+		if chunks[i] != w {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], w)
+		}
+	}
+}
+
+func TestUploadResumableDefaultsChunkSizeToFileSize(t *testing.T) {
+	var chunks []string
+	srv := newTestUploadServer(t, &chunks)
+	defer srv.Close()
+
+	content := "hello"
+	file := UploadFile{
+		Name:        "test.bin",
+		ContentType: "application/octet-stream",
+		Size:        int64(len(content)),
+		Reader:      strings.NewReader(content),
+	}
+
+	// ResumableChunkSize left at zero: should fall back to
+	// DefaultResumableChunkSize, then be capped down to file.Size rather
+	// than allocating an 8MiB buffer for a 5 byte file.
+	s := &MediaItemsService{c: newTestClient(srv)}
+
+	if _, err := s.uploadResumable(context.Background(), file); err != nil {
+		t.Fatalf("uploadResumable() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0] != "0:hello" {
+		t.Errorf("chunks = %v, want a single chunk [\"0:hello\"]", chunks)
+	}
+}
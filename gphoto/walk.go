@@ -0,0 +1,104 @@
+package gphoto
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// WalkMediaItemsOptions configures WalkMediaItems.
+type WalkMediaItemsOptions struct {
+	// Requests partitions the overall search into independent queries (e.g.
+	// one per album or date range) that are walked concurrently.
+	Requests []*MediaItemsSearchRequest
+	// Concurrency bounds how many Requests are walked at once. Defaults to 1.
+	Concurrency int
+}
+
+// WalkMediaItems fans out opts.Concurrency workers across opts.Requests,
+// each following its own MediaItemsSearchIterator, and merges their pages
+// into a single channel as they arrive. A given request's own items keep
+// that request's page order, but results from different requests
+// interleave in whatever order their partitions complete — nothing here
+// waits on a slower partition before emitting a faster one. Use
+// MediaItemResult.PartitionIndex if the caller needs to regroup by
+// request afterwards.
+func (c *Client) WalkMediaItems(ctx context.Context, opts WalkMediaItemsOptions) <-chan MediaItemResult {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type job struct {
+		index int
+		req   *MediaItemsSearchRequest
+	}
+
+	jobs := make(chan job)
+	out := make(chan MediaItemResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range jobs {
+				it := c.MediaItemsSearchIterator(ctx, j.req)
+				for {
+					item, err := it.Next()
+					if err == io.EOF {
+						break
+					}
+
+					result := MediaItemResult{MediaItem: item, Err: err, PartitionIndex: j.index}
+					select {
+					case out <- result:
+					case <-ctx.Done():
+						return
+					}
+					if err != nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for i, req := range opts.Requests {
+			select {
+			case jobs <- job{index: i, req: req}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// WalkAlbums is a WalkMediaItems convenience for walking several albums by
+// ID concurrently; results interleave across albums as they complete, see
+// WalkMediaItems.
+func (c *Client) WalkAlbums(ctx context.Context, albumIDs []string, concurrency int) <-chan MediaItemResult {
+	reqs := make([]*MediaItemsSearchRequest, len(albumIDs))
+	for i, albumID := range albumIDs {
+		reqs[i] = &MediaItemsSearchRequest{
+			PagerRequest: PagerRequest{PageSize: "100"},
+			AlbumID:      albumID,
+		}
+	}
+
+	return c.WalkMediaItems(ctx, WalkMediaItemsOptions{
+		Requests:    reqs,
+		Concurrency: concurrency,
+	})
+}
@@ -0,0 +1,101 @@
+package gphoto
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWalkMediaItemsInterleavesPartitions(t *testing.T) {
+	// The "slow" partition answers after a delay; "fast" answers
+	// immediately. With true parallel fan-out, fast's result must not wait
+	// behind slow's.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MediaItemsSearchRequest
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		if err := json.Unmarshal(buf, &req); err != nil {
+			t.Fatalf("unmarshaling request body: %v", err)
+		}
+
+		if req.AlbumID == "slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MediaItemsSearchResponse{
+			MediaItems: []*MediaItem{{ID: req.AlbumID}},
+		})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+
+	out := c.WalkMediaItems(context.Background(), WalkMediaItemsOptions{
+		Requests: []*MediaItemsSearchRequest{
+			{AlbumID: "slow"},
+			{AlbumID: "fast"},
+		},
+		Concurrency: 2,
+	})
+
+	var mu sync.Mutex
+	var order []string
+	for result := range out {
+		if result.Err != nil {
+			t.Fatalf("result.Err = %v", result.Err)
+		}
+		mu.Lock()
+		order = append(order, result.MediaItem.ID)
+		mu.Unlock()
+	}
+
+	if len(order) != 2 {
+		t.Fatalf("got %d results %v, want 2", len(order), order)
+	}
+	if order[0] != "fast" {
+		t.Errorf("first result = %q, want %q (the fast partition must not be blocked behind the slow one)", order[0], "fast")
+	}
+}
+
+func TestWalkMediaItemsStopsOnCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MediaItemsSearchResponse{
+			PagerResponse: PagerResponse{NextPageToken: "more"},
+			MediaItems:    []*MediaItem{{ID: "x"}},
+		})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out := c.WalkMediaItems(ctx, WalkMediaItemsOptions{
+		Requests:    []*MediaItemsSearchRequest{{AlbumID: "a"}},
+		Concurrency: 1,
+	})
+
+	<-out
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range out {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("WalkMediaItems did not close its output channel after ctx was cancelled")
+	}
+}
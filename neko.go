@@ -19,6 +19,7 @@ type config struct {
 	ClientID     string `envconfig:"GOOGLE_CLIENT_ID" required:"true"`
 	ClientSecret string `envconfig:"GOOGLE_CLIENT_SECRET" required:"true"`
 	RefreshToken string `envconfig:"GOOGLE_REFRESH_TOKEN"`
+	TokenFile    string `envconfig:"GOOGLE_TOKEN_FILE" default:"neko-token.json"`
 }
 
 func main() {
@@ -37,6 +38,14 @@ func main() {
 	oauth2.WithHTTPClient(&http.Client{
 		Timeout: 5 * time.Second,
 	})
+	oauth2.WithTokenStore(goauth2.NewFileTokenStore(cfg.TokenFile))
+
+	if err := oauth2.LoadFromStore(); err != nil {
+		log.Fatalf("%+v", err)
+	}
+	if cfg.RefreshToken == "" {
+		cfg.RefreshToken = oauth2.GetRefreshToken()
+	}
 
 	if cfg.RefreshToken == "" {
 		for {
@@ -85,7 +94,7 @@ func main() {
 		&http.Client{
 			Timeout: 5 * time.Second,
 		},
-		oauth2.GetAccessToken(),
+		oauth2,
 	)
 
 	resp, err := gphotoClient.MediaItemsSearch(